@@ -0,0 +1,81 @@
+// Package eventbus publishes events.Event to external message brokers —
+// NATS subjects, Kafka topics, or a generic gRPC Publish(topic, bytes)
+// service in the style of containerd's events API — turning the module
+// into a Lightning event bus that can feed downstream analytics or
+// alerting pipelines. Events are marshaled as protobuf, see event.proto.
+package eventbus
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hieblmi/go-lnd-router-events/eventbus/eventpb"
+	"github.com/hieblmi/go-lnd-router-events/events"
+)
+
+// TopicFunc maps an Event to the topic, subject, or key it should be
+// published under, so callers can shard delivery by peer pubkey, event
+// type, or anything else derived from the event.
+type TopicFunc func(event *events.Event) string
+
+// ByEventType is a TopicFunc that shards by event type: "forward" or
+// "invoice".
+func ByEventType(event *events.Event) string {
+	switch event.Type {
+	case events.Forward:
+		return "forward"
+	case events.SettledInvoice:
+		return "invoice"
+	default:
+		return "unknown"
+	}
+}
+
+// ByPeerPubKey is a TopicFunc that shards Forward events by the remote
+// peer's pubkey the HTLC arrived from. Invoice events, which have no
+// counterparty, fall back to "invoice", as do Forward events with an empty
+// FromPubKey, such as failed forwards.
+func ByPeerPubKey(event *events.Event) string {
+	if event.Type == events.Forward && event.FromPubKey != "" {
+		return event.FromPubKey
+	}
+	return "invoice"
+}
+
+// toProto converts an Event into the protobuf message event.proto declares
+// for its type.
+func toProto(event *events.Event) (proto.Message, error) {
+	switch event.Type {
+	case events.Forward:
+		return &eventpb.ForwardEvent{
+			FromPubKey:      event.FromPubKey,
+			FromAlias:       event.FromAlias,
+			ToPubKey:        event.ToPubKey,
+			ToAlias:         event.ToAlias,
+			IncomingMsat:    event.IncomingMSats,
+			OutgoingMsat:    event.OutgoingMSats,
+			ChanIdIn:        event.ChanId_In,
+			ChanIdOut:       event.ChanId_Out,
+			Success:         event.Success,
+			TimestampNs:     event.TimestampNs,
+			SettleLatencyNs: event.SettleLatencyNs,
+		}, nil
+	case events.SettledInvoice:
+		return &eventpb.InvoiceEvent{
+			IsSettled:        event.IsSettled,
+			SettleAmountMsat: event.SettleAmount_msat,
+			Preimage:         event.Preimage,
+		}, nil
+	default:
+		return nil, fmt.Errorf("eventbus: no protobuf mapping for event type %s", event.Type)
+	}
+}
+
+// marshal converts event into its protobuf wire representation.
+func marshal(event *events.Event) ([]byte, error) {
+	msg, err := toProto(event)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,60 @@
+// Package eventpb holds the Go types for eventbus/event.proto.
+//
+// These are hand-maintained stand-ins for what `protoc --go_out=.` would
+// normally generate. This tree doesn't have the protobuf toolchain wired
+// into its build yet, so regenerate this file with protoc-gen-go once it
+// is, rather than hand-editing struct tags further.
+package eventpb
+
+import "fmt"
+
+// ForwardEvent mirrors a settled or failed events.Event of type
+// events.Forward.
+type ForwardEvent struct {
+	FromPubKey      string `protobuf:"bytes,1,opt,name=from_pub_key,json=fromPubKey,proto3" json:"from_pub_key,omitempty"`
+	FromAlias       string `protobuf:"bytes,2,opt,name=from_alias,json=fromAlias,proto3" json:"from_alias,omitempty"`
+	ToPubKey        string `protobuf:"bytes,3,opt,name=to_pub_key,json=toPubKey,proto3" json:"to_pub_key,omitempty"`
+	ToAlias         string `protobuf:"bytes,4,opt,name=to_alias,json=toAlias,proto3" json:"to_alias,omitempty"`
+	IncomingMsat    uint64 `protobuf:"varint,5,opt,name=incoming_msat,json=incomingMsat,proto3" json:"incoming_msat,omitempty"`
+	OutgoingMsat    uint64 `protobuf:"varint,6,opt,name=outgoing_msat,json=outgoingMsat,proto3" json:"outgoing_msat,omitempty"`
+	ChanIdIn        uint64 `protobuf:"varint,7,opt,name=chan_id_in,json=chanIdIn,proto3" json:"chan_id_in,omitempty"`
+	ChanIdOut       uint64 `protobuf:"varint,8,opt,name=chan_id_out,json=chanIdOut,proto3" json:"chan_id_out,omitempty"`
+	Success         bool   `protobuf:"varint,9,opt,name=success,proto3" json:"success,omitempty"`
+	TimestampNs     int64  `protobuf:"varint,10,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
+	SettleLatencyNs int64  `protobuf:"varint,11,opt,name=settle_latency_ns,json=settleLatencyNs,proto3" json:"settle_latency_ns,omitempty"`
+}
+
+func (m *ForwardEvent) Reset()         { *m = ForwardEvent{} }
+func (m *ForwardEvent) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ForwardEvent) ProtoMessage()    {}
+
+// InvoiceEvent mirrors an events.Event of type events.SettledInvoice.
+type InvoiceEvent struct {
+	IsSettled        bool   `protobuf:"varint,1,opt,name=is_settled,json=isSettled,proto3" json:"is_settled,omitempty"`
+	SettleAmountMsat int64  `protobuf:"varint,2,opt,name=settle_amount_msat,json=settleAmountMsat,proto3" json:"settle_amount_msat,omitempty"`
+	Preimage         []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (m *InvoiceEvent) Reset()         { *m = InvoiceEvent{} }
+func (m *InvoiceEvent) String() string { return fmt.Sprintf("%+v", *m) }
+func (*InvoiceEvent) ProtoMessage()    {}
+
+// PublishRequest mirrors the PublishRequest message used by the
+// EventPublisher.Publish RPC. Event is the marshaled ForwardEvent or
+// InvoiceEvent. event.proto deliberately keeps this as bytes rather than
+// google.protobuf.Any for now; see the TODO on EventPublisher there.
+type PublishRequest struct {
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Event []byte `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+}
+
+func (m *PublishRequest) Reset()         { *m = PublishRequest{} }
+func (m *PublishRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*PublishRequest) ProtoMessage()    {}
+
+// PublishResponse mirrors the (empty) PublishResponse message.
+type PublishResponse struct{}
+
+func (m *PublishResponse) Reset()         { *m = PublishResponse{} }
+func (m *PublishResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*PublishResponse) ProtoMessage()    {}
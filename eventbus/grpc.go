@@ -0,0 +1,52 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+)
+
+// PublishClient is the Publish call of the EventPublisher service in
+// event.proto, reduced to a topic and the marshaled event bytes. This tree
+// holds no generated gRPC client for that service; PublishClient is an
+// interface so that GRPCPublisher works with any implementation of it,
+// including a fake without a real gRPC connection.
+type PublishClient interface {
+	Publish(ctx context.Context, topic string, eventProto []byte) error
+}
+
+// GRPCPublisher is an events.Observer that publishes every Event it
+// receives to a generic gRPC EventPublisher service, in the style of
+// containerd's events API.
+type GRPCPublisher struct {
+	client PublishClient
+	topic  TopicFunc
+}
+
+// NewGRPCPublisher creates a publisher that calls client.Publish for every
+// received event, sharding by topic.
+func NewGRPCPublisher(client PublishClient, topic TopicFunc) *GRPCPublisher {
+	return &GRPCPublisher{
+		client: client,
+		topic:  topic,
+	}
+}
+
+// GetName satisfies events.Observer.
+func (p *GRPCPublisher) GetName() string {
+	return "grpc-publisher"
+}
+
+// Update satisfies events.Observer, publishing event over gRPC.
+func (p *GRPCPublisher) Update(event *events.Event) {
+	raw, err := marshal(event)
+	if err != nil {
+		log.Printf("grpc publisher: cannot marshal event: %v\n", err)
+		return
+	}
+
+	if err := p.client.Publish(context.Background(), p.topic(event), raw); err != nil {
+		log.Printf("grpc publisher: cannot publish to topic %s: %v\n", p.topic(event), err)
+	}
+}
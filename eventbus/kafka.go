@@ -0,0 +1,55 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher is an events.Observer that publishes every Event it
+// receives to a Kafka topic chosen by a TopicFunc.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+	topic  TopicFunc
+}
+
+// NewKafkaPublisher creates a publisher that writes to brokers, sharding
+// outgoing events by topic.
+func NewKafkaPublisher(brokers []string, topic TopicFunc) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Balancer: &kafka.LeastBytes{},
+		},
+		topic: topic,
+	}
+}
+
+// GetName satisfies events.Observer.
+func (p *KafkaPublisher) GetName() string {
+	return "kafka-publisher"
+}
+
+// Update satisfies events.Observer, publishing event to its Kafka topic.
+func (p *KafkaPublisher) Update(event *events.Event) {
+	raw, err := marshal(event)
+	if err != nil {
+		log.Printf("kafka publisher: cannot marshal event: %v\n", err)
+		return
+	}
+
+	err = p.writer.WriteMessages(context.Background(), kafka.Message{
+		Topic: p.topic(event),
+		Value: raw,
+	})
+	if err != nil {
+		log.Printf("kafka publisher: cannot write message: %v\n", err)
+	}
+}
+
+// Close flushes and closes the underlying Kafka writer.
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
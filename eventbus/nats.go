@@ -0,0 +1,52 @@
+package eventbus
+
+import (
+	"log"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher is an events.Observer that publishes every Event it
+// receives to a NATS subject chosen by a TopicFunc.
+type NATSPublisher struct {
+	conn  *nats.Conn
+	topic TopicFunc
+}
+
+// NewNATSPublisher connects to the NATS server at url and returns a
+// publisher that shards outgoing events by topic.
+func NewNATSPublisher(url string, topic TopicFunc) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSPublisher{
+		conn:  conn,
+		topic: topic,
+	}, nil
+}
+
+// GetName satisfies events.Observer.
+func (p *NATSPublisher) GetName() string {
+	return "nats-publisher"
+}
+
+// Update satisfies events.Observer, publishing event to its NATS subject.
+func (p *NATSPublisher) Update(event *events.Event) {
+	raw, err := marshal(event)
+	if err != nil {
+		log.Printf("nats publisher: cannot marshal event: %v\n", err)
+		return
+	}
+
+	if err := p.conn.Publish(p.topic(event), raw); err != nil {
+		log.Printf("nats publisher: cannot publish to subject %s: %v\n", p.topic(event), err)
+	}
+}
+
+// Close drains and closes the NATS connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,102 @@
+// Package boltstore provides a bbolt-backed events.EventStore, persisting
+// every emitted Event to disk so that observers which register via
+// events.LNDEventListener.RegisterFrom can replay the backlog they missed
+// after downtime.
+package boltstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+	bolt "go.etcd.io/bbolt"
+)
+
+var eventsBucket = []byte("events")
+
+// Store is a bbolt-backed events.EventStore.
+type Store struct {
+	db *bolt.DB
+}
+
+// New opens (creating if necessary) a bbolt database at path and returns a
+// Store backed by it.
+func New(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open bbolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(eventsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot create events bucket: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Append satisfies events.EventStore, persisting event under the bucket's
+// next sequence number.
+func (s *Store) Append(event *events.Event) (*events.Event, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(eventsBucket)
+
+		idx, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		event.Index = idx
+
+		raw, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(indexKey(idx), raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// Since satisfies events.EventStore, returning every persisted event with
+// Index > sinceIndex, ordered by index ascending.
+func (s *Store) Since(sinceIndex uint64) ([]*events.Event, error) {
+	var backlog []*events.Event
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+
+		for k, v := c.Seek(indexKey(sinceIndex + 1)); k != nil; k, v = c.Next() {
+			var event events.Event
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			backlog = append(backlog, &event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backlog, nil
+}
+
+// Close satisfies events.EventStore, closing the underlying bbolt database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func indexKey(idx uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, idx)
+	return key
+}
@@ -0,0 +1,50 @@
+package boltstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+)
+
+func TestAppendSinceRoundTrip(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer store.Close()
+
+	for i := 0; i < 3; i++ {
+		event := &events.Event{Type: events.Forward, ChanId_In: uint64(i)}
+
+		stored, err := store.Append(event)
+		if err != nil {
+			t.Fatalf("Append() returned error: %v", err)
+		}
+		if stored.Index != uint64(i+1) {
+			t.Fatalf("Append() assigned index %d, want %d", stored.Index, i+1)
+		}
+	}
+
+	backlog, err := store.Since(1)
+	if err != nil {
+		t.Fatalf("Since() returned error: %v", err)
+	}
+	if len(backlog) != 2 {
+		t.Fatalf("Since(1) returned %d events, want 2", len(backlog))
+	}
+	for i, event := range backlog {
+		wantChanIn := uint64(i + 1)
+		if event.ChanId_In != wantChanIn {
+			t.Errorf("backlog[%d].ChanId_In = %d, want %d", i, event.ChanId_In, wantChanIn)
+		}
+	}
+
+	all, err := store.Since(0)
+	if err != nil {
+		t.Fatalf("Since(0) returned error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Since(0) returned %d events, want 3", len(all))
+	}
+}
@@ -3,17 +3,15 @@ package events
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightninglabs/lndclient"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
-	"github.com/lightningnetwork/lnd/macaroons"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"gopkg.in/macaroon.v2"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 type ObservableEventType int
@@ -36,14 +34,13 @@ func (e ObservableEventType) String() string {
 }
 
 type Observable interface {
-	Register(observer *Observer, e ObservableEventType)
+	Register(observer *Observer, e ObservableEventType, policy DeliveryPolicy)
+	RegisterFrom(observer *Observer, e ObservableEventType, sinceIndex uint64, policy DeliveryPolicy)
 	Deregister(observer *Observer, e ObservableEventType)
 	Start()
 	UpdateAll()
 }
 
-type LNDEventListener struct{}
-
 type Observer interface {
 	Update(event *Event)
 	GetName() string
@@ -51,6 +48,16 @@ type Observer interface {
 
 type Event struct {
 	Type ObservableEventType
+	// Index is the monotonically increasing position this event was
+	// assigned by the EventStore it was persisted to, used by
+	// RegisterFrom to resume a backlog.
+	Index uint64
+	// NodeAlias and NodePubKey identify the lnd node this event
+	// originated from. Set by LNDEventListener itself; only meaningful
+	// when events from several nodes are merged, e.g. by
+	// MultiNodeListener.
+	NodeAlias  string
+	NodePubKey string
 	// Forward fields
 	FromPubKey    string
 	FromAlias     string
@@ -62,6 +69,15 @@ type Event struct {
 	ChanId_Out    uint64
 	HtlcId_In     uint64
 	HtlcId_Out    uint64
+	// Success is false when a Forward event was reported for a HTLC that
+	// ultimately link-failed or forward-failed instead of settling.
+	Success bool
+	// TimestampNs is the lnd-reported time the underlying htlc event
+	// occurred, in unix nanoseconds.
+	TimestampNs int64
+	// SettleLatencyNs is the time between the originating ForwardEvent and
+	// its SettleEvent, in nanoseconds. Only set on settled Forward events.
+	SettleLatencyNs int64
 	// Invoice fields
 	IsSettled         bool
 	SettleAmount_msat int64
@@ -72,235 +88,567 @@ type Config struct {
 	MacaroonPath string
 	CertPath     string
 	RpcHost      string
+	// Network is the lnd network this node operates on, e.g.
+	// lndclient.NetworkMainnet.
+	Network lndclient.Network
+	// Store persists every emitted Event so that observers reconnecting
+	// via RegisterFrom can be caught up on the backlog they missed. If
+	// nil, a NoopEventStore is used and no backlog is ever available.
+	Store EventStore
+	// ForwardTTL bounds how long a HTLC forward is kept in the in-flight
+	// map waiting for a settle/fail event before it is swept away. If
+	// zero, defaultForwardTTL is used.
+	ForwardTTL time.Duration
 }
 
-var observers map[ObservableEventType][]Observer
-
-var forwardsInFlight map[uint64]*routerrpc.HtlcEvent
+// DeliveryPolicy controls what an observer's subscription does when its
+// delivery buffer is full.
+type DeliveryPolicy int
 
-var router routerrpc.RouterClient
-
-var lndcli lnrpc.LightningClient
+const (
+	// Block makes delivery wait for the observer to catch up. A slow
+	// observer with this policy can stall delivery of a given event to
+	// whichever other same-type observers persistAndUpdate/UpdateAll
+	// haven't reached yet, since they're delivered to sequentially, and
+	// the htlc/invoice subscription loop delivering that event waits
+	// with it before handling its next event. It never blocks
+	// Register/Deregister or appends from other goroutines, though: the
+	// subscriptions an event goes out to are snapshotted under subMu,
+	// and delivery happens after subMu is released.
+	Block DeliveryPolicy = iota
+	// DropOldest discards the oldest buffered event to make room for the
+	// new one.
+	DropOldest
+	// DropNewest discards the incoming event if the buffer is full.
+	DropNewest
+)
 
-var thisNodesPubKey string
+// subscriberBufferSize is the number of events buffered per subscription
+// before DeliveryPolicy takes effect.
+const subscriberBufferSize = 64
+
+// defaultForwardTTL bounds how long an unresolved HTLC forward is kept in
+// the in-flight map before being swept away.
+const defaultForwardTTL = 2 * time.Minute
+
+type subscription struct {
+	observer  Observer
+	eventType ObservableEventType
+	policy    DeliveryPolicy
+	cursor    uint64
+	ch        chan *Event
+
+	// done is closed exactly once, by stop, when the subscription is
+	// deregistered. deliver and run both select on it so that a
+	// deregistered subscription's channel never has to be closed out
+	// from under a concurrent send, which would panic.
+	done     chan struct{}
+	stopOnce sync.Once
+}
 
-// Reads lnd config parameters
-// Creates a new instance of router event listener that observers can subscribe to
-func New(config *Config) *LNDEventListener {
-	observers = make(map[ObservableEventType][]Observer)
-	forwardsInFlight = make(map[uint64]*routerrpc.HtlcEvent)
+// stop signals deliver and run to abandon this subscription. Safe to call
+// concurrently with deliver and more than once.
+func (s *subscription) stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
 
-	macaroonBytes, err := ioutil.ReadFile(config.MacaroonPath)
-	if err != nil {
-		log.Fatal("Cannot read macaroon file", err)
+// deliver hands event to the subscription's buffered channel, applying its
+// DeliveryPolicy if the buffer is full, unless the subscription has been
+// stopped, in which case event is silently dropped.
+func (s *subscription) deliver(event *Event) {
+	switch s.policy {
+	case DropNewest:
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		default:
+			log.Printf("Dropping newest event for observer %s: buffer full\n", s.observer.GetName())
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.ch <- event:
+				return
+			case <-s.done:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+			default:
+			}
+		}
+	default: // Block
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
 	}
+}
 
-	mac := &macaroon.Macaroon{}
-	if err = mac.UnmarshalBinary(macaroonBytes); err != nil {
-		log.Fatal("Cannot unmarshal macaroon", err)
+// run drains the subscription's channel, delivering events to its observer
+// on a dedicated goroutine so that a slow observer only holds up senders
+// once its buffer is full, and then only under the Block policy. It exits
+// as soon as the subscription is stopped, whether or not events remain
+// buffered.
+func (s *subscription) run() {
+	for {
+		select {
+		case event := <-s.ch:
+			s.observer.Update(event)
+			atomic.StoreUint64(&s.cursor, event.Index)
+		case <-s.done:
+			return
+		}
 	}
+}
 
-	err = os.Setenv("GRPC_SSL_CIPHER_SUITES", "HIGH+ECDSA")
-	if err != nil {
-		log.Fatal("Cannot set environment variable GRPC_SSL_CIPHER_SUITES")
-	}
+// forwardInFlight pairs an in-flight HtlcEvent with the time it was first
+// seen, so stale entries can be swept away.
+type forwardInFlight struct {
+	event  *routerrpc.HtlcEvent
+	seenAt time.Time
+}
+
+// LNDEventListener connects to a single lnd node and lets Observers
+// subscribe to the forward and invoice events it emits.
+type LNDEventListener struct {
+	subMu         sync.RWMutex
+	subscriptions []*subscription
+
+	// latestIndex is the index of the most recently persisted event, used
+	// so that a plain Register (as opposed to RegisterFrom) starts an
+	// observer at the live stream without replaying any backlog.
+	latestIndex uint64
 
-	creds, err := credentials.NewClientTLSFromFile(config.CertPath, "")
+	// forwardsInFlight maps an in-flight key to a forwardInFlight. It's a
+	// sync.Map since it's written from the htlc subscription goroutine and
+	// read/swept from the TTL sweep goroutine concurrently.
+	forwardsInFlight sync.Map
+
+	forwardTTL time.Duration
+
+	store EventStore
+
+	lnd *lndclient.LndServices
+
+	thisNodesPubKey string
+	thisNodesAlias  string
+}
+
+// New connects to the lnd node described by config via lndclient, which
+// takes care of macaroon auth, TLS, and version-checking, and returns a new
+// instance of router event listener that observers can subscribe to.
+func New(config *Config) *LNDEventListener {
+	services, err := lndclient.NewLndServices(&lndclient.LndServicesConfig{
+		LndAddress:         config.RpcHost,
+		Network:            config.Network,
+		CustomMacaroonPath: config.MacaroonPath,
+		TLSPath:            config.CertPath,
+	})
 	if err != nil {
-		log.Fatal("Cannot load credentials from CertPath: %s", config.CertPath)
+		log.Fatal("Cannot connect to lnd via lndclient: ", err)
 	}
 
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(creds),
-		grpc.WithPerRPCCredentials(macaroons.NewMacaroonCredential(mac)),
+	store := config.Store
+	if store == nil {
+		store = NewNoopEventStore()
 	}
 
-	conn, err := grpc.Dial(config.RpcHost, opts...)
-	if err != nil {
-		log.Fatalf("Cannot connect to %s with cert %s\n", config.RpcHost, config.CertPath)
+	forwardTTL := config.ForwardTTL
+	if forwardTTL <= 0 {
+		forwardTTL = defaultForwardTTL
 	}
 
-	router = routerrpc.NewRouterClient(conn)
-	lndcli = lnrpc.NewLightningClient(conn)
-	info, err := lndcli.GetInfo(context.Background(), &lnrpc.GetInfoRequest{})
-
-	if err != nil {
-		log.Fatal("Could not retrieve this node's pub key %#v", err)
+	r := &LNDEventListener{
+		store:           store,
+		forwardTTL:      forwardTTL,
+		lnd:             services,
+		thisNodesPubKey: services.NodePubkey.String(),
+		thisNodesAlias:  services.NodeAlias,
 	}
-	thisNodesPubKey = info.IdentityPubkey
 
-	return &LNDEventListener{}
+	go r.sweepForwardsInFlight()
+
+	return r
 }
 
 func (r *LNDEventListener) Start() {
-
-	var wg sync.WaitGroup
+	var done []chan struct{}
 
 	for e := ObservableEventType(0); e < eventLimit; e++ {
-		_, exists := observers[e]
-		if exists {
-			wg.Add(1)
-			switch e {
-			case SettledInvoice:
-				go r.subscribeInvoiceSettlements()
-			case Forward:
-				go r.subscribeHtlcEvents()
-			default:
-			}
+		if !r.hasSubscribers(e) {
+			continue
+		}
+
+		finished := make(chan struct{})
+		done = append(done, finished)
+
+		switch e {
+		case SettledInvoice:
+			go func() {
+				defer close(finished)
+				r.subscribeInvoiceSettlements()
+			}()
+		case Forward:
+			go func() {
+				defer close(finished)
+				r.subscribeHtlcEvents()
+			}()
+		default:
 		}
 	}
 
-	wg.Wait()
+	for _, finished := range done {
+		<-finished
+	}
+}
+
+func (r *LNDEventListener) hasSubscribers(t ObservableEventType) bool {
+	r.subMu.RLock()
+	defer r.subMu.RUnlock()
+
+	for _, sub := range r.subscriptions {
+		if sub.eventType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// sweepForwardsInFlight periodically drops in-flight forwards that have
+// been waiting longer than forwardTTL for a settle/fail event, bounding
+// memory use when HTLCs never resolve.
+func (r *LNDEventListener) sweepForwardsInFlight() {
+	ticker := time.NewTicker(r.forwardTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-r.forwardTTL)
+		r.forwardsInFlight.Range(func(key, value interface{}) bool {
+			if value.(forwardInFlight).seenAt.Before(cutoff) {
+				r.forwardsInFlight.Delete(key)
+				log.Printf("Swept stale in-flight forward with key %v after %s\n", key, r.forwardTTL)
+			}
+			return true
+		})
+	}
 }
 
 func (r *LNDEventListener) subscribeHtlcEvents() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	events, err := router.SubscribeHtlcEvents(context.Background(), &routerrpc.SubscribeHtlcEventsRequest{})
+	htlcEvents, errChan, err := r.lnd.Router.SubscribeHtlcEvents(ctx)
 	if err != nil {
 		log.Fatalf("Cannot subscribe to Htlc events: %#v\n", err)
 	}
 
 	log.Println("Listening for Htlc events...")
 	for {
-		event, err := events.Recv()
-		if err != nil {
-			log.Println("got error from events.Recv()", err)
-			return
-		}
-
-		// calculate key for in flight forward events to identify settlement details
-		inFlightKey := event.IncomingChannelId + event.OutgoingChannelId + event.IncomingHtlcId + event.OutgoingHtlcId
-
-		switch event.Event.(type) {
-		case *routerrpc.HtlcEvent_SettleEvent:
-			e, exists := forwardsInFlight[inFlightKey]
-			if !exists {
-				continue
+		select {
+		case event, ok := <-htlcEvents:
+			if !ok {
+				return
 			}
+			r.handleHtlcEvent(event)
 
-			delete(forwardsInFlight, inFlightKey)
-
-			settleEvent := settleEventDetails(e)
-			r.UpdateAll(settleEvent)
-
-		case *routerrpc.HtlcEvent_LinkFailEvent:
-			delete(forwardsInFlight, inFlightKey)
-		case *routerrpc.HtlcEvent_ForwardFailEvent:
-			delete(forwardsInFlight, inFlightKey)
-		case *routerrpc.HtlcEvent_ForwardEvent:
-			forwardsInFlight[inFlightKey] = event
+		case err := <-errChan:
+			log.Println("got error from htlc event subscription", err)
+			return
 		}
-		log.Printf("Size of inflight forward map: %d\n", len(forwardsInFlight))
 	}
 }
 
-func (r *LNDEventListener) subscribeInvoiceSettlements() {
-
-	req := &lnrpc.InvoiceSubscription{}
+func (r *LNDEventListener) handleHtlcEvent(event *routerrpc.HtlcEvent) {
+	// calculate key for in flight forward events to identify settlement details
+	inFlightKey := event.IncomingChannelId + event.OutgoingChannelId + event.IncomingHtlcId + event.OutgoingHtlcId
 
-	ctx, cancelInvoiceSubscription := context.WithCancel(context.Background())
+	switch event.Event.(type) {
+	case *routerrpc.HtlcEvent_SettleEvent:
+		v, exists := r.forwardsInFlight.Load(inFlightKey)
+		if !exists {
+			return
+		}
 
-	defer cancelInvoiceSubscription()
+		r.forwardsInFlight.Delete(inFlightKey)
+
+		settleEvent := r.settleEventDetails(v.(forwardInFlight).event, event)
+		r.persistAndUpdate(settleEvent)
+
+	case *routerrpc.HtlcEvent_LinkFailEvent:
+		r.forwardsInFlight.Delete(inFlightKey)
+		r.persistAndUpdate(&Event{
+			Type:        Forward,
+			Success:     false,
+			NodeAlias:   r.thisNodesAlias,
+			NodePubKey:  r.thisNodesPubKey,
+			ChanId_In:   event.IncomingChannelId,
+			ChanId_Out:  event.OutgoingChannelId,
+			HtlcId_In:   event.IncomingHtlcId,
+			HtlcId_Out:  event.OutgoingHtlcId,
+			TimestampNs: int64(event.TimestampNs),
+		})
+	case *routerrpc.HtlcEvent_ForwardFailEvent:
+		r.forwardsInFlight.Delete(inFlightKey)
+		r.persistAndUpdate(&Event{
+			Type:        Forward,
+			Success:     false,
+			NodeAlias:   r.thisNodesAlias,
+			NodePubKey:  r.thisNodesPubKey,
+			ChanId_In:   event.IncomingChannelId,
+			ChanId_Out:  event.OutgoingChannelId,
+			HtlcId_In:   event.IncomingHtlcId,
+			HtlcId_Out:  event.OutgoingHtlcId,
+			TimestampNs: int64(event.TimestampNs),
+		})
+	case *routerrpc.HtlcEvent_ForwardEvent:
+		r.forwardsInFlight.Store(inFlightKey, forwardInFlight{
+			event:  event,
+			seenAt: time.Now(),
+		})
+	}
+}
 
-	invoiceSubscription, err := lndcli.SubscribeInvoices(ctx, req)
+func (r *LNDEventListener) subscribeInvoiceSettlements() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
+	invoiceUpdates, errChan, err := r.lnd.Client.SubscribeInvoices(ctx, lndclient.SubscribeInvoicesRequest{})
 	if err != nil {
 		log.Fatalf("Cannot subscribe to invoices: %#v\n", err)
 	}
 
 	log.Println("Listening for invoice events...")
 	for {
-		invoiceUpdate, err := invoiceSubscription.Recv()
-		if err != nil {
-			log.Println("got error from events.Recv()", err)
+		select {
+		case invoiceUpdate, ok := <-invoiceUpdates:
+			if !ok {
+				return
+			}
+			log.Printf("Invoice update: %#v\n", invoiceUpdate)
+			r.persistAndUpdate(&Event{
+				Type:              SettledInvoice,
+				NodeAlias:         r.thisNodesAlias,
+				NodePubKey:        r.thisNodesPubKey,
+				IsSettled:         invoiceUpdate.State == channeldb.ContractSettled,
+				SettleAmount_msat: int64(invoiceUpdate.AmountPaid),
+				Preimage:          invoiceUpdate.Preimage[:],
+			})
+
+		case err := <-errChan:
+			log.Println("got error from invoice subscription", err)
 			return
 		}
-		log.Printf("Invoice update: %#v\n", invoiceUpdate)
-		r.UpdateAll(&Event{
-			Type:              SettledInvoice,
-			IsSettled:         invoiceUpdate.Settled,
-			SettleAmount_msat: invoiceUpdate.AmtPaidMsat,
-			Preimage:          invoiceUpdate.RPreimage,
-		})
 	}
-
 }
 
-func settleEventDetails(event *routerrpc.HtlcEvent) *Event {
+func (r *LNDEventListener) settleEventDetails(forwardEvent, settleEvent *routerrpc.HtlcEvent) *Event {
 
 	var fromAlias, toAlias, fromPubKey, toPubKey string
 
-	incomingChanInfo, err := lndcli.GetChanInfo(context.Background(), &lnrpc.ChanInfoRequest{ChanId: event.IncomingChannelId})
+	incomingChanInfo, err := r.lnd.Client.GetChanInfo(context.Background(), forwardEvent.IncomingChannelId)
 
 	if err != nil {
 		log.Println("Cannot get incoming channel info", err)
 		fromPubKey = "Incoming pub key not available"
 		fromAlias = "Info not available"
 	} else {
-		if incomingChanInfo.Node1Pub == thisNodesPubKey {
-			fromAlias = fmt.Sprintf("%s", getNodeAlias(incomingChanInfo.Node2Pub))
-			fromPubKey = incomingChanInfo.Node2Pub
+		if incomingChanInfo.Node1.String() == r.thisNodesPubKey {
+			fromAlias = fmt.Sprintf("%s", r.getNodeAlias(incomingChanInfo.Node2))
+			fromPubKey = incomingChanInfo.Node2.String()
 		} else {
-			fromAlias = fmt.Sprintf("%s", getNodeAlias(incomingChanInfo.Node1Pub))
-			fromPubKey = incomingChanInfo.Node1Pub
+			fromAlias = fmt.Sprintf("%s", r.getNodeAlias(incomingChanInfo.Node1))
+			fromPubKey = incomingChanInfo.Node1.String()
 		}
 	}
 
-	outgoingChanInfo, err := lndcli.GetChanInfo(context.Background(), &lnrpc.ChanInfoRequest{ChanId: event.OutgoingChannelId})
+	outgoingChanInfo, err := r.lnd.Client.GetChanInfo(context.Background(), forwardEvent.OutgoingChannelId)
 
 	if err != nil {
 		log.Println("Cannot get outgoing channel info", err)
 		toPubKey = "Outgoing pub key not available"
 		toAlias = "Nowhere - you've been paid"
 	} else {
-		if outgoingChanInfo.Node1Pub == thisNodesPubKey {
-			toPubKey = outgoingChanInfo.Node2Pub
-			toAlias = fmt.Sprintf("%s", getNodeAlias(outgoingChanInfo.Node2Pub))
+		if outgoingChanInfo.Node1.String() == r.thisNodesPubKey {
+			toPubKey = outgoingChanInfo.Node2.String()
+			toAlias = fmt.Sprintf("%s", r.getNodeAlias(outgoingChanInfo.Node2))
 		} else {
-			toPubKey = outgoingChanInfo.Node1Pub
-			toAlias = fmt.Sprintf("%s", getNodeAlias(outgoingChanInfo.Node1Pub))
+			toPubKey = outgoingChanInfo.Node1.String()
+			toAlias = fmt.Sprintf("%s", r.getNodeAlias(outgoingChanInfo.Node1))
 		}
 	}
 
 	return &Event{
-		Type:          Forward,
-		FromPubKey:    fromPubKey,
-		FromAlias:     fromAlias,
-		ToPubKey:      toPubKey,
-		ToAlias:       toAlias,
-		IncomingMSats: event.GetForwardEvent().Info.IncomingAmtMsat,
-		OutgoingMSats: event.GetForwardEvent().Info.OutgoingAmtMsat,
+		Type:            Forward,
+		Success:         true,
+		NodeAlias:       r.thisNodesAlias,
+		NodePubKey:      r.thisNodesPubKey,
+		FromPubKey:      fromPubKey,
+		FromAlias:       fromAlias,
+		ToPubKey:        toPubKey,
+		ToAlias:         toAlias,
+		ChanId_In:       forwardEvent.IncomingChannelId,
+		ChanId_Out:      forwardEvent.OutgoingChannelId,
+		HtlcId_In:       forwardEvent.IncomingHtlcId,
+		HtlcId_Out:      forwardEvent.OutgoingHtlcId,
+		TimestampNs:     int64(settleEvent.TimestampNs),
+		SettleLatencyNs: int64(settleEvent.TimestampNs) - int64(forwardEvent.TimestampNs),
+		IncomingMSats:   forwardEvent.GetForwardEvent().Info.IncomingAmtMsat,
+		OutgoingMSats:   forwardEvent.GetForwardEvent().Info.OutgoingAmtMsat,
 	}
 }
 
-func (r *LNDEventListener) Register(o Observer, t ObservableEventType) {
-	log.Printf("Registering observer %s for %s events\n", o.GetName(), t.String())
-	observers[t] = append(observers[t], o)
+// Register subscribes o to events of type t, starting from the next event
+// emitted. No backlog is replayed. policy decides what happens once o's
+// delivery buffer is full.
+func (r *LNDEventListener) Register(o Observer, t ObservableEventType, policy DeliveryPolicy) {
+	r.RegisterFrom(o, t, atomic.LoadUint64(&r.latestIndex), policy)
+}
+
+// RegisterFrom subscribes o to events of type t, first replaying every
+// persisted event of that type with an index greater than sinceIndex,
+// mirroring lnd's add_index/settle_index model for SubscribeInvoices. This
+// lets an observer that reconnects after downtime catch up on the backlog
+// it missed. The backlog is delivered after o has joined the live stream,
+// so live events emitted meanwhile may reach o before the remaining
+// backlog events do; each event is still delivered exactly once. Once the
+// buffered channel backing this subscription is full, policy decides
+// whether delivery blocks or an event is dropped: with DropOldest or
+// DropNewest a slow observer never stalls delivery to other observers or
+// the htlc/invoice subscription loops, with Block it does.
+func (r *LNDEventListener) RegisterFrom(o Observer, t ObservableEventType, sinceIndex uint64, policy DeliveryPolicy) {
+	log.Printf("Registering observer %s for %s events since index %d\n", o.GetName(), t.String(), sinceIndex)
+
+	sub := &subscription{
+		observer:  o,
+		eventType: t,
+		policy:    policy,
+		cursor:    sinceIndex,
+		ch:        make(chan *Event, subscriberBufferSize),
+		done:      make(chan struct{}),
+	}
+	go sub.run()
+
+	// Fetching the backlog and adding sub to r.subscriptions happen under
+	// the same lock persistAndUpdate holds around appending an event to
+	// the store and snapshotting the subscriptions an event goes out to.
+	// That makes the two critical sections mutually exclusive, so any
+	// given event is either: already reflected in Since()'s result below,
+	// in which case persistAndUpdate's snapshot (taken first) can't have
+	// included sub, since it wasn't registered yet; or not yet reflected
+	// in Since()'s result, in which case sub is already registered by the
+	// time persistAndUpdate takes its snapshot, and it's delivered live
+	// instead. Either way sub sees it exactly once.
+	r.subMu.Lock()
+	backlog, err := r.store.Since(sinceIndex)
+	if err != nil {
+		log.Printf("Cannot load backlog for observer %s: %v\n", o.GetName(), err)
+	}
+	r.subscriptions = append(r.subscriptions, sub)
+	r.subMu.Unlock()
+
+	for _, event := range backlog {
+		if event.Type != t {
+			continue
+		}
+		sub.deliver(event)
+	}
 }
 
+// Deregister removes o's subscription to events of type t, leaving any
+// other subscriptions o holds for other event types untouched.
 func (r *LNDEventListener) Deregister(o Observer, t ObservableEventType) {
 	log.Printf("Deregistering observer %s for %s events\n", o.GetName(), t.String())
-	_, exists := observers[t]
-	if exists {
-		delete(observers, t)
-	} else {
-		log.Printf("Cannot deregister. EventConsumer does not exists")
+
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	for i, sub := range r.subscriptions {
+		if sub.observer == o && sub.eventType == t {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			sub.stop()
+			return
+		}
 	}
+	log.Printf("Cannot deregister. EventConsumer does not exists")
 }
 
+// ForwardsInFlight returns the number of htlc forwards that have been seen
+// but have not yet settled, link-failed, or forward-failed.
+func (r *LNDEventListener) ForwardsInFlight() int {
+	count := 0
+	r.forwardsInFlight.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// persistAndUpdate writes event to the configured EventStore, which assigns
+// it its index, before fanning it out to subscribers. Events are always
+// persisted before delivery so that an observer's RegisterFrom backlog is
+// never missing an event its live stream already received. On a store error
+// the event is dropped rather than delivered: without an index it can't be
+// placed in the backlog, so delivering it live would leave RegisterFrom
+// replays inconsistent with what the live stream already sent.
+//
+// The store append and subscriptions snapshot below happen under the same
+// lock RegisterFrom holds around its backlog read and subscribe, so the two
+// can't race each other into delivering an event to an observer twice or
+// not at all; see the comment on RegisterFrom. subMu is released before
+// delivery so that a Block-policy observer stalling on a full buffer can't
+// also stall Register/Deregister or appends from other goroutines; the
+// caller itself waits until delivery is done.
+func (r *LNDEventListener) persistAndUpdate(event *Event) {
+	r.subMu.Lock()
+	stored, err := r.store.Append(event)
+	if err != nil {
+		r.subMu.Unlock()
+		log.Printf("Cannot persist event, dropping it: %v\n", err)
+		return
+	}
+	atomic.StoreUint64(&r.latestIndex, stored.Index)
+	subs := r.subscriptionsForLocked(stored.Type)
+	r.subMu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(stored)
+	}
+}
+
+// UpdateAll fans event out to every subscription for its event type, without
+// persisting it first. Like persistAndUpdate, the subscriptions are
+// snapshotted under subMu and delivered to after it's released, so a
+// Block-policy observer stalling on a full buffer can't also stall
+// Register/Deregister.
 func (r *LNDEventListener) UpdateAll(event *Event) {
-	for _, o := range observers[event.Type] {
-		o.Update(event)
+	r.subMu.RLock()
+	subs := r.subscriptionsForLocked(event.Type)
+	r.subMu.RUnlock()
+
+	for _, sub := range subs {
+		sub.deliver(event)
 	}
 }
 
-func getNodeAlias(pubKey string) string {
+// subscriptionsForLocked returns the subscriptions subscribed to t. Callers
+// must hold subMu, for reading or writing, when calling this.
+func (r *LNDEventListener) subscriptionsForLocked(t ObservableEventType) []*subscription {
+	var subs []*subscription
+	for _, sub := range r.subscriptions {
+		if sub.eventType == t {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
 
-	nodeInfo, err := lndcli.GetNodeInfo(context.Background(), &lnrpc.NodeInfoRequest{
-		PubKey: pubKey,
-	})
+func (r *LNDEventListener) getNodeAlias(pubKey route.Vertex) string {
+	nodeInfo, err := r.lnd.Client.GetNodeInfo(context.Background(), pubKey, false)
 	if err != nil {
 		log.Printf("Cannot retrieve node info for pubkey %s\n", pubKey)
+		return ""
 	}
-	return nodeInfo.Node.Alias
+	return nodeInfo.Alias
 }
@@ -0,0 +1,53 @@
+package events
+
+// MultiNodeListener fans the events of several LNDEventListeners, one per
+// configured lnd node, into a single Observer stream. Every Event it
+// forwards carries the NodeAlias/NodePubKey of the node it originated
+// from, so a routing operator running several nodes can observe forwards
+// across their whole fleet through one subscription.
+type MultiNodeListener struct {
+	listeners []*LNDEventListener
+}
+
+// NewMultiNodeListener connects to every lnd node described by configs and
+// returns a MultiNodeListener that fans their events together.
+func NewMultiNodeListener(configs []*Config) *MultiNodeListener {
+	m := &MultiNodeListener{}
+	for _, cfg := range configs {
+		m.listeners = append(m.listeners, New(cfg))
+	}
+	return m
+}
+
+// Register subscribes o to events of type t from every underlying node.
+func (m *MultiNodeListener) Register(o Observer, t ObservableEventType, policy DeliveryPolicy) {
+	for _, l := range m.listeners {
+		l.Register(o, t, policy)
+	}
+}
+
+// Deregister removes o's subscription to events of type t from every
+// underlying node.
+func (m *MultiNodeListener) Deregister(o Observer, t ObservableEventType) {
+	for _, l := range m.listeners {
+		l.Deregister(o, t)
+	}
+}
+
+// Start begins streaming events from every underlying node, blocking until
+// all of them stop.
+func (m *MultiNodeListener) Start() {
+	done := make(chan struct{}, len(m.listeners))
+
+	for _, l := range m.listeners {
+		l := l
+		go func() {
+			l.Start()
+			done <- struct{}{}
+		}()
+	}
+
+	for range m.listeners {
+		<-done
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import "testing"
+
+type recordingObserver struct{ name string }
+
+func (o *recordingObserver) GetName() string { return o.name }
+func (o *recordingObserver) Update(*Event)   {}
+
+func TestSubscriptionDeliverDropOldest(t *testing.T) {
+	sub := &subscription{
+		observer: &recordingObserver{name: "test"},
+		policy:   DropOldest,
+		ch:       make(chan *Event, 2),
+	}
+
+	sub.deliver(&Event{Index: 1})
+	sub.deliver(&Event{Index: 2})
+	sub.deliver(&Event{Index: 3})
+
+	if got := len(sub.ch); got != 2 {
+		t.Fatalf("buffer length = %d, want 2", got)
+	}
+
+	first := <-sub.ch
+	second := <-sub.ch
+	if first.Index != 2 || second.Index != 3 {
+		t.Fatalf("got indices %d, %d; want oldest (1) dropped, 2 and 3 kept", first.Index, second.Index)
+	}
+}
+
+func TestSubscriptionDeliverDropNewest(t *testing.T) {
+	sub := &subscription{
+		observer: &recordingObserver{name: "test"},
+		policy:   DropNewest,
+		ch:       make(chan *Event, 2),
+	}
+
+	sub.deliver(&Event{Index: 1})
+	sub.deliver(&Event{Index: 2})
+	sub.deliver(&Event{Index: 3})
+
+	if got := len(sub.ch); got != 2 {
+		t.Fatalf("buffer length = %d, want 2", got)
+	}
+
+	first := <-sub.ch
+	second := <-sub.ch
+	if first.Index != 1 || second.Index != 2 {
+		t.Fatalf("got indices %d, %d; want incoming event 3 dropped, 1 and 2 kept", first.Index, second.Index)
+	}
+}
+
+func TestSubscriptionDeliverBlock(t *testing.T) {
+	sub := &subscription{
+		observer: &recordingObserver{name: "test"},
+		policy:   Block,
+		ch:       make(chan *Event, 1),
+	}
+
+	sub.deliver(&Event{Index: 1})
+
+	done := make(chan struct{})
+	go func() {
+		sub.deliver(&Event{Index: 2})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("deliver() with Block policy returned before buffer had room")
+	default:
+	}
+
+	<-sub.ch
+	<-done
+}
@@ -0,0 +1,296 @@
+// Package gateway exposes the events produced by events.LNDEventListener
+// to non-Go clients over HTTP, mirroring the way lnd itself fronts its
+// gRPC services with a REST reverse proxy. Clients can subscribe over
+// Server-Sent Events or a WebSocket, and every event is serialized as JSON.
+package gateway
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hieblmi/go-lnd-router-events/events"
+)
+
+// GatewayConfig configures the HTTP gateway.
+type GatewayConfig struct {
+	// Addr is the address the gateway listens on, e.g. ":8080".
+	Addr string
+	// BearerToken is the macaroon-style token clients must present in an
+	// `Authorization: Bearer <token>` header. If empty, auth is disabled.
+	BearerToken string
+	// ClientBufferSize is how many events are buffered per connected
+	// client; while a client's buffer is full, new events for it are
+	// dropped.
+	ClientBufferSize int
+}
+
+// Gateway is an Observer that fans events.Event out to connected HTTP
+// clients over SSE and WebSocket.
+type Gateway struct {
+	listener *events.LNDEventListener
+	cfg      GatewayConfig
+	srv      *http.Server
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+}
+
+type client struct {
+	eventTypes map[events.ObservableEventType]bool
+	out        chan *events.Event
+}
+
+// NewHTTPGateway creates a Gateway that registers itself as an Observer on
+// listener for all event types and serves them over HTTP according to cfg.
+func NewHTTPGateway(listener *events.LNDEventListener, cfg GatewayConfig) *Gateway {
+	if cfg.ClientBufferSize <= 0 {
+		cfg.ClientBufferSize = 64
+	}
+
+	g := &Gateway{
+		listener: listener,
+		cfg:      cfg,
+		clients:  make(map[*client]struct{}),
+	}
+
+	listener.Register(g, events.Forward, events.DropOldest)
+	listener.Register(g, events.SettledInvoice, events.DropOldest)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/events/stream", g.requireAuth(g.serveSSE))
+	mux.HandleFunc("/v1/events/ws", g.requireAuth(g.serveWS))
+	mux.HandleFunc("/swagger.json", g.serveSwagger)
+
+	g.srv = &http.Server{
+		Addr:    cfg.Addr,
+		Handler: mux,
+	}
+
+	return g
+}
+
+// GetName satisfies events.Observer.
+func (g *Gateway) GetName() string {
+	return "http-gateway"
+}
+
+// Update satisfies events.Observer, fanning event out to every subscribed
+// client whose filter matches the event's type.
+func (g *Gateway) Update(event *events.Event) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for c := range g.clients {
+		if !c.eventTypes[event.Type] {
+			continue
+		}
+		select {
+		case c.out <- event:
+		default:
+			log.Printf("gateway: client buffer full, dropping event")
+		}
+	}
+}
+
+// ListenAndServe starts the gateway's HTTP server, blocking until it is
+// stopped or encounters an error.
+func (g *Gateway) ListenAndServe() error {
+	return g.srv.ListenAndServe()
+}
+
+// Close shuts the gateway's HTTP server down.
+func (g *Gateway) Close() error {
+	return g.srv.Close()
+}
+
+func (g *Gateway) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.cfg.BearerToken == "" {
+			next(w, r)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if auth != "Bearer "+g.cfg.BearerToken {
+			http.Error(w, "invalid or missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func parseEventTypes(r *http.Request) map[events.ObservableEventType]bool {
+	want := map[events.ObservableEventType]bool{
+		events.Forward:        true,
+		events.SettledInvoice: true,
+	}
+
+	types := r.URL.Query().Get("type")
+	if types == "" {
+		return want
+	}
+
+	filtered := make(map[events.ObservableEventType]bool)
+	for _, t := range strings.Split(types, ",") {
+		switch strings.TrimSpace(t) {
+		case "forward":
+			filtered[events.Forward] = true
+		case "invoice":
+			filtered[events.SettledInvoice] = true
+		}
+	}
+	return filtered
+}
+
+func (g *Gateway) addClient(eventTypes map[events.ObservableEventType]bool) *client {
+	c := &client{
+		eventTypes: eventTypes,
+		out:        make(chan *events.Event, g.cfg.ClientBufferSize),
+	}
+
+	g.mu.Lock()
+	g.clients[c] = struct{}{}
+	g.mu.Unlock()
+
+	return c
+}
+
+func (g *Gateway) removeClient(c *client) {
+	g.mu.Lock()
+	delete(g.clients, c)
+	g.mu.Unlock()
+	close(c.out)
+}
+
+func (g *Gateway) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	c := g.addClient(parseEventTypes(r))
+	defer g.removeClient(c)
+
+	for {
+		select {
+		case event, ok := <-c.out:
+			if !ok {
+				return
+			}
+			b, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("gateway: cannot marshal event: %v", err)
+				continue
+			}
+			if _, err := w.Write([]byte("data: " + string(b) + "\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+func (g *Gateway) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("gateway: cannot upgrade to websocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	c := g.addClient(parseEventTypes(r))
+	defer g.removeClient(c)
+
+	for event := range c.out {
+		if err := conn.WriteJSON(event); err != nil {
+			log.Printf("gateway: cannot write to websocket client: %v", err)
+			return
+		}
+	}
+}
+
+func (g *Gateway) serveSwagger(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(swaggerJSON))
+}
+
+const swaggerJSON = `{
+  "swagger": "2.0",
+  "info": {
+    "title": "go-lnd-router-events gateway",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/v1/events/stream": {
+      "get": {
+        "summary": "Subscribe to routing and invoice events via Server-Sent Events",
+        "parameters": [
+          {
+            "name": "type",
+            "in": "query",
+            "type": "string",
+            "description": "Comma separated list of event types to receive: forward, invoice"
+          }
+        ],
+        "produces": ["text/event-stream"],
+        "responses": {
+          "200": {
+            "description": "A stream of Event objects",
+            "schema": { "$ref": "#/definitions/Event" }
+          }
+        }
+      }
+    },
+    "/v1/events/ws": {
+      "get": {
+        "summary": "Subscribe to routing and invoice events via WebSocket",
+        "responses": {
+          "101": { "description": "Switching Protocols" }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Event": {
+      "type": "object",
+      "properties": {
+        "Type": { "type": "integer" },
+        "Index": { "type": "integer" },
+        "NodeAlias": { "type": "string" },
+        "NodePubKey": { "type": "string" },
+        "FromPubKey": { "type": "string" },
+        "FromAlias": { "type": "string" },
+        "IncomingMSats": { "type": "integer" },
+        "ToAlias": { "type": "string" },
+        "ToPubKey": { "type": "string" },
+        "OutgoingMSats": { "type": "integer" },
+        "ChanId_In": { "type": "integer" },
+        "ChanId_Out": { "type": "integer" },
+        "HtlcId_In": { "type": "integer" },
+        "HtlcId_Out": { "type": "integer" },
+        "Success": { "type": "boolean" },
+        "TimestampNs": { "type": "integer" },
+        "SettleLatencyNs": { "type": "integer" },
+        "IsSettled": { "type": "boolean" },
+        "SettleAmount_msat": { "type": "integer" },
+        "Preimage": { "type": "string" }
+      }
+    }
+  }
+}`
@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+)
+
+func TestRequireAuth(t *testing.T) {
+	g := &Gateway{cfg: GatewayConfig{BearerToken: "secret"}}
+
+	called := false
+	handler := g.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/events/stream", nil)
+	handler(rec, req)
+
+	if called {
+		t.Fatal("handler called without a bearer token")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	called = false
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/v1/events/stream", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	handler(rec, req)
+
+	if called {
+		t.Fatal("handler called with a wrong bearer token")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	called = false
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/v1/events/stream", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	handler(rec, req)
+
+	if !called {
+		t.Fatal("handler not called with the correct bearer token")
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRequireAuthDisabled(t *testing.T) {
+	g := &Gateway{cfg: GatewayConfig{}}
+
+	called := false
+	handler := g.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/events/stream", nil)
+	handler(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Fatal("handler not called when BearerToken is empty")
+	}
+}
+
+func TestParseEventTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  map[events.ObservableEventType]bool
+	}{
+		{
+			name:  "no filter defaults to all types",
+			query: "",
+			want: map[events.ObservableEventType]bool{
+				events.Forward:        true,
+				events.SettledInvoice: true,
+			},
+		},
+		{
+			name:  "forward only",
+			query: "type=forward",
+			want:  map[events.ObservableEventType]bool{events.Forward: true},
+		},
+		{
+			name:  "invoice only",
+			query: "type=invoice",
+			want:  map[events.ObservableEventType]bool{events.SettledInvoice: true},
+		},
+		{
+			name:  "both types",
+			query: "type=forward,invoice",
+			want: map[events.ObservableEventType]bool{
+				events.Forward:        true,
+				events.SettledInvoice: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/events/stream?"+tt.query, nil)
+			got := parseEventTypes(req)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseEventTypes() = %v, want %v", got, tt.want)
+			}
+			for t2, want := range tt.want {
+				if got[t2] != want {
+					t.Errorf("parseEventTypes()[%v] = %v, want %v", t2, got[t2], want)
+				}
+			}
+		})
+	}
+}
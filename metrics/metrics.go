@@ -0,0 +1,192 @@
+// Package metrics exposes routing and invoice events from the events
+// package as Prometheus/OpenMetrics collectors, so operators can scrape
+// them directly or point Grafana at the resulting HTTP endpoint.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hieblmi/go-lnd-router-events/events"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "lnd"
+
+// Collector implements events.Observer and turns every Event it receives
+// into Prometheus counter, histogram, and gauge updates.
+type Collector struct {
+	listener *events.LNDEventListener
+
+	forwardTotal        *prometheus.CounterVec
+	forwardMsatTotal    *prometheus.CounterVec
+	forwardFeeMsatTotal *prometheus.CounterVec
+	forwardFailTotal    *prometheus.CounterVec
+	invoiceSettledTotal prometheus.Counter
+
+	htlcSize        prometheus.Histogram
+	settleLatencyMs prometheus.Histogram
+
+	forwardsInFlight prometheus.GaugeFunc
+}
+
+// NewCollector creates a Collector and registers it with listener for
+// Forward and SettledInvoice events.
+func NewCollector(listener *events.LNDEventListener) *Collector {
+	// Aliases are excluded from the label set: they're derivable from the
+	// pubkeys already here, and an unbounded, operator-changeable aliases
+	// value. Labeling on it too would double the series' cardinality for
+	// no added information.
+	labels := []string{"from_pubkey", "to_pubkey", "chan_id_in", "chan_id_out"}
+
+	c := &Collector{
+		listener: listener,
+		forwardTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forward_total",
+			Help:      "Total number of successfully forwarded HTLCs.",
+		}, labels),
+		forwardMsatTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forward_msat_total",
+			Help:      "Total amount forwarded in millisatoshis.",
+		}, labels),
+		forwardFeeMsatTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forward_fee_msat_total",
+			Help:      "Total routing fees earned in millisatoshis.",
+		}, labels),
+		forwardFailTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "forward_fail_total",
+			Help:      "Total number of HTLCs that link-failed or forward-failed.",
+		}, []string{"chan_id_in", "chan_id_out"}),
+		invoiceSettledTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "invoice_settled_total",
+			Help:      "Total number of settled invoices.",
+		}),
+		htlcSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "forward_htlc_msat",
+			Help:      "Size distribution of forwarded HTLCs in millisatoshis.",
+			Buckets:   prometheus.ExponentialBuckets(1000, 4, 10),
+		}),
+		settleLatencyMs: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "forward_settle_latency_ms",
+			Help:      "Time between a ForwardEvent and its SettleEvent, in milliseconds.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		}),
+	}
+
+	c.forwardsInFlight = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "forwards_in_flight",
+		Help:      "Number of HTLC forwards seen but not yet settled or failed.",
+	}, func() float64 {
+		return float64(listener.ForwardsInFlight())
+	})
+
+	// Metrics are best-effort: if the collector ever falls behind, drop
+	// stale events rather than stalling htlc/invoice processing.
+	listener.Register(c, events.Forward, events.DropOldest)
+	listener.Register(c, events.SettledInvoice, events.DropOldest)
+
+	return c
+}
+
+// GetName satisfies events.Observer.
+func (c *Collector) GetName() string {
+	return "metrics-collector"
+}
+
+// Update satisfies events.Observer, recording metrics for the given event.
+func (c *Collector) Update(event *events.Event) {
+	switch event.Type {
+	case events.Forward:
+		chanIn := strconv.FormatUint(event.ChanId_In, 10)
+		chanOut := strconv.FormatUint(event.ChanId_Out, 10)
+
+		if !event.Success {
+			c.forwardFailTotal.WithLabelValues(chanIn, chanOut).Inc()
+			return
+		}
+
+		labels := prometheus.Labels{
+			"from_pubkey": event.FromPubKey,
+			"to_pubkey":   event.ToPubKey,
+			"chan_id_in":  chanIn,
+			"chan_id_out": chanOut,
+		}
+
+		fee := event.IncomingMSats - event.OutgoingMSats
+		c.forwardTotal.With(labels).Inc()
+		c.forwardMsatTotal.With(labels).Add(float64(event.IncomingMSats))
+		c.forwardFeeMsatTotal.With(labels).Add(float64(fee))
+		c.htlcSize.Observe(float64(event.IncomingMSats))
+
+		if event.SettleLatencyNs > 0 {
+			c.settleLatencyMs.Observe(float64(event.SettleLatencyNs) / float64(time.Millisecond))
+		}
+
+	case events.SettledInvoice:
+		if event.IsSettled {
+			c.invoiceSettledTotal.Inc()
+		}
+	}
+}
+
+// Registry returns a prometheus.Registry with all of the collector's
+// metrics registered, ready to be served or merged into an existing registry.
+func (c *Collector) Registry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		c.forwardTotal,
+		c.forwardMsatTotal,
+		c.forwardFeeMsatTotal,
+		c.forwardFailTotal,
+		c.invoiceSettledTotal,
+		c.htlcSize,
+		c.settleLatencyMs,
+		c.forwardsInFlight,
+	)
+	return reg
+}
+
+// Server serves the collector's metrics over HTTP in the Prometheus
+// exposition format.
+type Server struct {
+	addr   string
+	srv    *http.Server
+	client *Collector
+}
+
+// NewServer creates a metrics HTTP server listening on addr, scraping the
+// metrics collected by c at GET /metrics.
+func NewServer(addr string, c *Collector) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(c.Registry(), promhttp.HandlerOpts{}))
+
+	return &Server{
+		addr:   addr,
+		client: c,
+		srv: &http.Server{
+			Addr:    addr,
+			Handler: mux,
+		},
+	}
+}
+
+// ListenAndServe starts serving metrics, blocking until the server is
+// stopped or encounters an error.
+func (s *Server) ListenAndServe() error {
+	return s.srv.ListenAndServe()
+}
+
+// Close shuts the metrics server down.
+func (s *Server) Close() error {
+	return s.srv.Close()
+}